@@ -0,0 +1,75 @@
+package gcp
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/projectdiscovery/gologger"
+)
+
+// caiAsset mirrors the subset of the Cloud Asset Inventory export schema
+// (https://cloud.google.com/asset-inventory/docs/exporting-to-json) that
+// cloudlist can populate from its own resource metadata.
+type caiAsset struct {
+	Name      string         `json:"name"`
+	AssetType string         `json:"assetType"`
+	Resource  caiAssetDetail `json:"resource"`
+}
+
+type caiAssetDetail struct {
+	Data caiAssetData `json:"data"`
+}
+
+type caiAssetData struct {
+	Project     string            `json:"project,omitempty"`
+	Location    string            `json:"location,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	PublicIPv4  string            `json:"publicIPv4,omitempty"`
+	PrivateIpv4 string            `json:"privateIpv4,omitempty"`
+}
+
+// FormatCAI renders GCP resources as newline-delimited Cloud Asset Inventory
+// compatible JSON, one asset per line, so cloudlist output can feed directly
+// into pipelines that already consume the CAI schema.
+//
+// Only resources with a SelfLink and ResourceType are eligible: today that is
+// cloud-sql, load-balancer, pubsub, and iam, since those are the only
+// collectors populating GCP-native identity metadata. dns, gke, compute, s3,
+// cloud-function, and cloud-run still only produce flat host/IP resources
+// and are skipped here; use the default flat output for those until they are
+// updated to populate the same fields. FormatCAI logs a warning naming how
+// many resources it had to skip so this gap isn't silently mistaken for a
+// complete asset export.
+func FormatCAI(resources *schema.Resources) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+
+	var skipped int
+	for _, resource := range resources.Items {
+		if resource.SelfLink == "" || resource.ResourceType == "" {
+			skipped++
+			continue
+		}
+		asset := caiAsset{
+			Name:      resource.SelfLink,
+			AssetType: resource.ResourceType,
+			Resource: caiAssetDetail{
+				Data: caiAssetData{
+					Project:     resource.Project,
+					Location:    resource.Location,
+					Labels:      resource.Labels,
+					PublicIPv4:  resource.PublicIPv4,
+					PrivateIpv4: resource.PrivateIpv4,
+				},
+			},
+		}
+		if err := encoder.Encode(asset); err != nil {
+			return nil, err
+		}
+	}
+	if skipped > 0 {
+		gologger.Warning().Msgf("CAI output is missing %d resource(s) whose collector doesn't populate SelfLink/ResourceType yet\n", skipped)
+	}
+	return buf.Bytes(), nil
+}
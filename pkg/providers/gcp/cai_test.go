@@ -0,0 +1,38 @@
+package gcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+func TestFormatCAISkipsResourcesWithoutIdentityMetadata(t *testing.T) {
+	resources := schema.NewResources()
+	resources.Append(schema.Resource{Provider: providerName, DNSName: "no-metadata.example.com"})
+	resources.Append(schema.Resource{
+		Provider:     providerName,
+		SelfLink:     "//sqladmin.googleapis.com/projects/p/instances/db",
+		ResourceType: sqlInstanceResourceType,
+		Project:      "p",
+	})
+
+	data, err := FormatCAI(resources)
+	if err != nil {
+		t.Fatalf("FormatCAI returned error: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected exactly 1 CAI asset line, got %d: %q", len(lines), data)
+	}
+
+	var asset caiAsset
+	if err := json.Unmarshal([]byte(lines[0]), &asset); err != nil {
+		t.Fatalf("could not unmarshal CAI asset: %s", err)
+	}
+	if asset.AssetType != sqlInstanceResourceType {
+		t.Fatalf("expected assetType %q, got %q", sqlInstanceResourceType, asset.AssetType)
+	}
+}
@@ -2,37 +2,60 @@ package gcp
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"cloud.google.com/go/compute/metadata"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 	"github.com/projectdiscovery/gologger"
 	errorutil "github.com/projectdiscovery/utils/errors"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/api/cloudfunctions/v1"
 	"google.golang.org/api/cloudresourcemanager/v1"
 	"google.golang.org/api/compute/v1"
 	container "google.golang.org/api/container/v1beta1"
 	"google.golang.org/api/dns/v1"
+	iam "google.golang.org/api/iam/v1"
+	"google.golang.org/api/option"
+	pubsub "google.golang.org/api/pubsub/v1"
 	run "google.golang.org/api/run/v1"
+	sqladmin "google.golang.org/api/sqladmin/v1beta4"
 	"google.golang.org/api/storage/v1"
 )
 
 // Provider is a data provider for gcp API
 type Provider struct {
-	dns       *dns.Service
-	gke       *container.Service
-	compute   *compute.Service
-	storage   *storage.Service
-	functions *cloudfunctions.Service
-	run       *run.APIService
-	services  schema.ServiceMap
-	id        string
-	projects  []string
+	dns            *dns.Service
+	gke            *container.Service
+	compute        *compute.Service
+	storage        *storage.Service
+	functions      *cloudfunctions.Service
+	run            *run.APIService
+	sql            *sqladmin.Service
+	pubsub         *pubsub.Service
+	iam            *iam.Service
+	loadBalancer   bool
+	services       schema.ServiceMap
+	id             string
+	projects       []string
+	maxConcurrency int
+	stateBucket    string
 }
 
-var Services = []string{"dns", "gke", "compute", "s3", "cloud-function", "cloud-run"}
+var Services = []string{"dns", "gke", "compute", "s3", "cloud-function", "cloud-run", "cloud-sql", "load-balancer", "pubsub", "iam"}
 
 const serviceAccountJSON = "gcp_service_account_key"
 const providerName = "gcp"
+const projectsOption = "gcp_projects"
+const maxConcurrencyOption = "gcp_max_concurrency"
+const defaultMaxConcurrency = 10
+
+// scanTimeout bounds the total time a single Resources call may take, so a
+// slow or hanging project cannot stall the whole scan indefinitely.
+const scanTimeout = 15 * time.Minute
 
 // Name returns the name of the provider
 func (p *Provider) Name() string {
@@ -51,10 +74,6 @@ func (p *Provider) Services() []string {
 
 // New creates a new provider client for gcp API
 func New(options schema.OptionBlock) (*Provider, error) {
-	JSONData, ok := options.GetMetadata(serviceAccountJSON)
-	if !ok {
-		return nil, errorutil.New("could not get API Key")
-	}
 	id, _ := options.GetMetadata("id")
 
 	provider := &Provider{id: id}
@@ -77,9 +96,16 @@ func New(options schema.OptionBlock) (*Provider, error) {
 	}
 	provider.services = services
 
-	creds, err := register(context.Background(), []byte(JSONData))
+	provider.maxConcurrency = defaultMaxConcurrency
+	if raw, ok := options.GetMetadata(maxConcurrencyOption); ok {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+			provider.maxConcurrency = parsed
+		}
+	}
+
+	creds, err := register(context.Background(), options)
 	if err != nil {
-		return nil, errorutil.NewWithErr(err).Msgf("could not register gcp service account")
+		return nil, errorutil.NewWithErr(err).Msgf("could not register gcp credentials")
 	}
 	if services.Has("dns") {
 		dnsService, err := dns.NewService(context.Background(), creds)
@@ -127,80 +153,260 @@ func New(options schema.OptionBlock) (*Provider, error) {
 		provider.run = cloudRunService
 	}
 
-	projects := []string{}
-	manager, err := cloudresourcemanager.NewService(context.Background(), creds)
+	if services.Has("cloud-sql") {
+		sqlService, err := sqladmin.NewService(context.Background(), creds)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not create sql admin service with api key")
+		}
+		provider.sql = sqlService
+	}
+
+	if services.Has("load-balancer") {
+		if provider.compute == nil {
+			computeService, err := compute.NewService(context.Background(), creds)
+			if err != nil {
+				return nil, errorutil.NewWithErr(err).Msgf("could not create compute service with api key")
+			}
+			provider.compute = computeService
+		}
+		provider.loadBalancer = true
+	}
+
+	if services.Has("pubsub") {
+		pubsubService, err := pubsub.NewService(context.Background(), creds)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not create pubsub service with api key")
+		}
+		provider.pubsub = pubsubService
+	}
+
+	if services.Has("iam") {
+		iamService, err := iam.NewService(context.Background(), creds)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not create iam service with api key")
+		}
+		provider.iam = iamService
+	}
+
+	if bucket, ok := options.GetMetadata(stateBucketOption); ok && bucket != "" {
+		provider.stateBucket = bucket
+		if provider.storage == nil {
+			storageService, err := storage.NewService(context.Background(), creds)
+			if err != nil {
+				return nil, errorutil.NewWithErr(err).Msgf("could not create storage service for watch state")
+			}
+			provider.storage = storageService
+		}
+	}
+
+	projects, err := discoverProjects(context.Background(), creds, options)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not discover gcp projects")
+	}
+	provider.projects = projects
+	return provider, nil
+}
+
+// discoverProjects resolves the list of projects to enumerate resources in.
+// If gcp_projects is set, it is used verbatim, letting accounts that cannot
+// call Resource Manager still enumerate resources in known projects. Failing
+// that, it falls back to cloudresourcemanager.Projects.List, and finally, when
+// running on GCE without Resource Manager access, to the instance's own project.
+func discoverProjects(ctx context.Context, creds option.ClientOption, options schema.OptionBlock) ([]string, error) {
+	if projectsCSV, ok := options.GetMetadata(projectsOption); ok && projectsCSV != "" {
+		var projects []string
+		for _, project := range strings.Split(projectsCSV, ",") {
+			if project = strings.TrimSpace(project); project != "" {
+				projects = append(projects, project)
+			}
+		}
+		return projects, nil
+	}
+
+	manager, err := cloudresourcemanager.NewService(ctx, creds)
 	if err != nil {
-		return nil, errorutil.NewWithErr(err).Msgf("could not list projects")
+		return nil, errorutil.NewWithErr(err).Msgf("could not create resource manager service")
 	}
-	list := manager.Projects.List()
-	err = list.Pages(context.Background(), func(resp *cloudresourcemanager.ListProjectsResponse) error {
+	var projects []string
+	listErr := manager.Projects.List().Pages(ctx, func(resp *cloudresourcemanager.ListProjectsResponse) error {
 		for _, project := range resp.Projects {
 			projects = append(projects, project.ProjectId)
 		}
 		return nil
 	})
-	provider.projects = projects
-	return provider, err
+	if listErr == nil && len(projects) > 0 {
+		return projects, nil
+	}
+
+	if metadata.OnGCE() {
+		if id, metaErr := metadata.ProjectID(); metaErr == nil && id != "" {
+			return []string{id}, nil
+		}
+	}
+	if listErr != nil {
+		return nil, listErr
+	}
+	return projects, nil
 }
 
-// Resources returns the provider for an resource deployment source.
+// Resources returns the provider for an resource deployment source. Service x
+// project work items are fanned out onto a bounded worker pool (sized by
+// gcp_max_concurrency) so a large org with many projects and services doesn't
+// pay for fully serial, I/O-bound API calls.
 func (p *Provider) Resources(ctx context.Context) (*schema.Resources, error) {
+	ctx, cancel := context.WithTimeout(ctx, scanTimeout)
+	defer cancel()
+
 	finalResources := schema.NewResources()
+	var mu sync.Mutex
+	merge := func(data *schema.Resources) {
+		mu.Lock()
+		defer mu.Unlock()
+		finalResources.Merge(data)
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(p.maxConcurrency)
 
+	// Collectors below are not yet per-project aware upstream, so each runs
+	// as a single work item that loops its own projects internally.
 	if p.dns != nil {
-		cloudDNSProvider := &cloudDNSProvider{dns: p.dns, id: p.id, projects: p.projects}
-		zones, err := cloudDNSProvider.GetResource(ctx)
-		if err != nil {
-			return nil, err
-		}
-		finalResources.Merge(zones)
+		group.Go(func() error {
+			cloudDNSProvider := &cloudDNSProvider{dns: p.dns, id: p.id, projects: p.projects}
+			zones, err := cloudDNSProvider.GetResource(groupCtx)
+			if err != nil {
+				return err
+			}
+			merge(zones)
+			return nil
+		})
 	}
 
 	if p.gke != nil {
-		GKEProvider := &gkeProvider{svc: p.gke, id: p.id, projects: p.projects}
-		gkeData, err := GKEProvider.GetResource(ctx)
-		if err != nil {
-			gologger.Warning().Msgf("Could not get GKE resources: %s\n", err)
-		}
-		finalResources.Merge(gkeData)
+		group.Go(func() error {
+			GKEProvider := &gkeProvider{svc: p.gke, id: p.id, projects: p.projects}
+			gkeData, err := GKEProvider.GetResource(groupCtx)
+			if err != nil {
+				gologger.Warning().Msgf("Could not get GKE resources: %s\n", err)
+			}
+			merge(gkeData)
+			return nil
+		})
 	}
 
-	if p.compute != nil {
-		VMProvider := &cloudVMProvider{compute: p.compute, id: p.id, projects: p.projects}
-		vmData, err := VMProvider.GetResource(ctx)
-		if err != nil {
-			return nil, err
+	if p.compute != nil && p.services.Has("compute") {
+		group.Go(func() error {
+			VMProvider := &cloudVMProvider{compute: p.compute, id: p.id, projects: p.projects}
+			vmData, err := VMProvider.GetResource(groupCtx)
+			if err != nil {
+				return err
+			}
+			merge(vmData)
+			return nil
+		})
+	}
+
+	if p.storage != nil && p.services.Has("s3") {
+		group.Go(func() error {
+			cloudStorageProvider := &cloudStorageProvider{id: p.id, storage: p.storage, projects: p.projects}
+			storageData, err := cloudStorageProvider.GetResource(groupCtx)
+			if err != nil {
+				return err
+			}
+			merge(storageData)
+			return nil
+		})
+	}
+
+	if p.functions != nil {
+		group.Go(func() error {
+			cloudFunctionsProvider := &cloudFunctionsProvider{id: p.id, functions: p.functions, projects: p.projects}
+			functionsData, err := cloudFunctionsProvider.GetResource(groupCtx)
+			if err != nil {
+				return err
+			}
+			merge(functionsData)
+			return nil
+		})
+	}
+
+	if p.run != nil {
+		group.Go(func() error {
+			cloudRunProvider := &cloudRunProvider{id: p.id, run: p.run, projects: p.projects}
+			cloudRunData, err := cloudRunProvider.GetResource(groupCtx)
+			if err != nil {
+				return err
+			}
+			merge(cloudRunData)
+			return nil
+		})
+	}
+
+	// Newer collectors fan out per project, so each project is a separate
+	// work item in the same bounded pool.
+	if p.sql != nil {
+		sqlProvider := &cloudSQLProvider{id: p.id, sql: p.sql}
+		for _, project := range p.projects {
+			project := project
+			group.Go(func() error {
+				data, err := sqlProvider.GetResourceForProject(groupCtx, project)
+				if err != nil {
+					return err
+				}
+				merge(data)
+				return nil
+			})
 		}
-		finalResources.Merge(vmData)
 	}
 
-	if p.storage != nil {
-		cloudStorageProvider := &cloudStorageProvider{id: p.id, storage: p.storage, projects: p.projects}
-		storageData, err := cloudStorageProvider.GetResource(ctx)
-		if err != nil {
-			return nil, err
+	if p.loadBalancer && p.compute != nil {
+		lbProvider := &cloudLBProvider{id: p.id, compute: p.compute}
+		for _, project := range p.projects {
+			project := project
+			group.Go(func() error {
+				data, err := lbProvider.GetResourceForProject(groupCtx, project)
+				if err != nil {
+					return err
+				}
+				merge(data)
+				return nil
+			})
 		}
-		finalResources.Merge(storageData)
 	}
 
-	if p.functions != nil {
-		cloudFunctionsProvider := &cloudFunctionsProvider{id: p.id, functions: p.functions, projects: p.projects}
-		functionsData, err := cloudFunctionsProvider.GetResource(ctx)
-		if err != nil {
-			return nil, err
+	if p.pubsub != nil {
+		pubsubProvider := &cloudPubSubProvider{id: p.id, pubsub: p.pubsub}
+		for _, project := range p.projects {
+			project := project
+			group.Go(func() error {
+				data, err := pubsubProvider.GetResourceForProject(groupCtx, project)
+				if err != nil {
+					return err
+				}
+				merge(data)
+				return nil
+			})
 		}
-		finalResources.Merge(functionsData)
 	}
 
-	if p.run != nil {
-		cloudRunProvider := &cloudRunProvider{id: p.id, run: p.run, projects: p.projects}
-		cloudRunData, err := cloudRunProvider.GetResource(ctx)
-		if err != nil {
-			return nil, err
+	if p.iam != nil {
+		iamProvider := &cloudIAMProvider{id: p.id, iam: p.iam}
+		for _, project := range p.projects {
+			project := project
+			group.Go(func() error {
+				data, err := iamProvider.GetResourceForProject(groupCtx, project)
+				if err != nil {
+					return err
+				}
+				merge(data)
+				return nil
+			})
 		}
-		finalResources.Merge(cloudRunData)
 	}
 
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
 	return finalResources, nil
 }
 
@@ -213,7 +419,7 @@ func (p *Provider) Verify(ctx context.Context) error {
 	// For extra verification, try a minimal API call on one service
 	for _, project := range p.projects {
 		var success bool
-		if p.compute != nil {
+		if p.compute != nil && p.services.Has("compute") {
 			_, err := p.compute.Regions.List(project).Do()
 			if err != nil {
 				return errorutil.NewWithErr(err).Msgf("failed to verify compute service access")
@@ -225,7 +431,7 @@ func (p *Provider) Verify(ctx context.Context) error {
 				return errorutil.NewWithErr(err).Msgf("failed to verify DNS service access")
 			}
 			success = true
-		} else if p.storage != nil {
+		} else if p.storage != nil && p.services.Has("s3") {
 			_, err := p.storage.Buckets.List(project).Do()
 			if err != nil {
 				return errorutil.NewWithErr(err).Msgf("failed to verify storage service access")
@@ -243,6 +449,30 @@ func (p *Provider) Verify(ctx context.Context) error {
 				return errorutil.NewWithErr(err).Msgf("failed to verify run service access")
 			}
 			success = true
+		} else if p.sql != nil {
+			_, err := p.sql.Instances.List(project).Do()
+			if err != nil {
+				return errorutil.NewWithErr(err).Msgf("failed to verify sql admin service access")
+			}
+			success = true
+		} else if p.pubsub != nil {
+			_, err := p.pubsub.Projects.Topics.List(fmt.Sprintf("projects/%s", project)).Do()
+			if err != nil {
+				return errorutil.NewWithErr(err).Msgf("failed to verify pubsub service access")
+			}
+			success = true
+		} else if p.iam != nil {
+			_, err := p.iam.Projects.ServiceAccounts.List(fmt.Sprintf("projects/%s", project)).Do()
+			if err != nil {
+				return errorutil.NewWithErr(err).Msgf("failed to verify iam service access")
+			}
+			success = true
+		} else if p.loadBalancer && p.compute != nil {
+			_, err := p.compute.GlobalForwardingRules.List(project).Do()
+			if err != nil {
+				return errorutil.NewWithErr(err).Msgf("failed to verify load balancer service access")
+			}
+			success = true
 		}
 		// For any one service to be successful, we can return nil
 		if success {
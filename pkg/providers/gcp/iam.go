@@ -0,0 +1,45 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	iam "google.golang.org/api/iam/v1"
+)
+
+// cloudIAMProvider is an instance of the IAM provider for GCP, used to
+// enumerate service account principals in a project.
+type cloudIAMProvider struct {
+	id  string
+	iam *iam.Service
+}
+
+// serviceAccountResourceType is the Cloud Asset Inventory resource type for an IAM service account.
+const serviceAccountResourceType = "iam.googleapis.com/ServiceAccount"
+
+// GetResourceForProject returns IAM service account resources for a single project.
+func (d *cloudIAMProvider) GetResourceForProject(ctx context.Context, project string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	name := fmt.Sprintf("projects/%s", project)
+
+	err := withRetry(ctx, func() error {
+		return d.iam.Projects.ServiceAccounts.List(name).Pages(ctx, func(resp *iam.ListServiceAccountsResponse) error {
+			for _, account := range resp.Accounts {
+				list.Append(schema.Resource{
+					Provider:     providerName,
+					ID:           d.id,
+					DNSName:      account.Email,
+					SelfLink:     account.Name,
+					Project:      project,
+					ResourceType: serviceAccountResourceType,
+				})
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
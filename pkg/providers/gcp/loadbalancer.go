@@ -0,0 +1,155 @@
+package gcp
+
+import (
+	"context"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	compute "google.golang.org/api/compute/v1"
+)
+
+// cloudLBProvider is an instance of the Load Balancer provider for GCP
+type cloudLBProvider struct {
+	id      string
+	compute *compute.Service
+}
+
+// forwardingRuleResourceType is the Cloud Asset Inventory resource type for a forwarding rule.
+const forwardingRuleResourceType = "compute.googleapis.com/ForwardingRule"
+
+// Cloud Asset Inventory resource types for the target proxies a forwarding
+// rule points at.
+const (
+	targetHTTPProxyResourceType  = "compute.googleapis.com/TargetHttpProxy"
+	targetHTTPSProxyResourceType = "compute.googleapis.com/TargetHttpsProxy"
+	targetTCPProxyResourceType   = "compute.googleapis.com/TargetTcpProxy"
+)
+
+// GetResourceForProject returns global and regional forwarding rule resources for a single project.
+func (d *cloudLBProvider) GetResourceForProject(ctx context.Context, project string) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	err := withRetry(ctx, func() error {
+		return d.compute.GlobalForwardingRules.List(project).Pages(ctx, func(resp *compute.ForwardingRuleList) error {
+			for _, rule := range resp.Items {
+				list.Append(schema.Resource{
+					Provider:     providerName,
+					ID:           d.id,
+					DNSName:      rule.Name,
+					Public:       true,
+					PublicIPv4:   rule.IPAddress,
+					SelfLink:     rule.SelfLink,
+					Project:      project,
+					Location:     "global",
+					ResourceType: forwardingRuleResourceType,
+					Labels:       rule.Labels,
+				})
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	regionErr := withRetry(ctx, func() error {
+		return d.compute.Regions.List(project).Pages(ctx, func(resp *compute.RegionList) error {
+			for _, region := range resp.Items {
+				err := d.compute.ForwardingRules.List(project, region.Name).Pages(ctx, func(resp *compute.ForwardingRuleList) error {
+					for _, rule := range resp.Items {
+						resource := schema.Resource{
+							Provider:     providerName,
+							ID:           d.id,
+							DNSName:      rule.Name,
+							SelfLink:     rule.SelfLink,
+							Project:      project,
+							Location:     region.Name,
+							ResourceType: forwardingRuleResourceType,
+							Labels:       rule.Labels,
+						}
+						if rule.LoadBalancingScheme == "INTERNAL" || rule.LoadBalancingScheme == "INTERNAL_MANAGED" {
+							resource.Private = true
+							resource.PrivateIpv4 = rule.IPAddress
+						} else {
+							resource.Public = true
+							resource.PublicIPv4 = rule.IPAddress
+						}
+						list.Append(resource)
+					}
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if regionErr != nil {
+		return nil, regionErr
+	}
+
+	if err := d.appendTargetProxies(ctx, project, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// appendTargetProxies adds the global target HTTP(S)/TCP proxies that
+// forwarding rules point at, so load balancer frontends can be enumerated
+// alongside the rules themselves.
+func (d *cloudLBProvider) appendTargetProxies(ctx context.Context, project string, list *schema.Resources) error {
+	if err := withRetry(ctx, func() error {
+		return d.compute.TargetHttpProxies.List(project).Pages(ctx, func(resp *compute.TargetHttpProxyList) error {
+			for _, proxy := range resp.Items {
+				list.Append(schema.Resource{
+					Provider:     providerName,
+					ID:           d.id,
+					DNSName:      proxy.Name,
+					SelfLink:     proxy.SelfLink,
+					Project:      project,
+					Location:     "global",
+					ResourceType: targetHTTPProxyResourceType,
+				})
+			}
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+
+	if err := withRetry(ctx, func() error {
+		return d.compute.TargetHttpsProxies.List(project).Pages(ctx, func(resp *compute.TargetHttpsProxyList) error {
+			for _, proxy := range resp.Items {
+				list.Append(schema.Resource{
+					Provider:     providerName,
+					ID:           d.id,
+					DNSName:      proxy.Name,
+					SelfLink:     proxy.SelfLink,
+					Project:      project,
+					Location:     "global",
+					ResourceType: targetHTTPSProxyResourceType,
+				})
+			}
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+
+	return withRetry(ctx, func() error {
+		return d.compute.TargetTcpProxies.List(project).Pages(ctx, func(resp *compute.TargetTcpProxyList) error {
+			for _, proxy := range resp.Items {
+				list.Append(schema.Resource{
+					Provider:     providerName,
+					ID:           d.id,
+					DNSName:      proxy.Name,
+					SelfLink:     proxy.SelfLink,
+					Project:      project,
+					Location:     "global",
+					ResourceType: targetTCPProxyResourceType,
+				})
+			}
+			return nil
+		})
+	})
+}
@@ -0,0 +1,68 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+// cloudPubSubProvider is an instance of the Pub/Sub provider for GCP
+type cloudPubSubProvider struct {
+	id     string
+	pubsub *pubsub.Service
+}
+
+// Cloud Asset Inventory resource types for Pub/Sub assets.
+const (
+	pubsubTopicResourceType        = "pubsub.googleapis.com/Topic"
+	pubsubSubscriptionResourceType = "pubsub.googleapis.com/Subscription"
+)
+
+// GetResourceForProject returns Pub/Sub topic and subscription resources for a single project.
+func (d *cloudPubSubProvider) GetResourceForProject(ctx context.Context, project string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	parent := fmt.Sprintf("projects/%s", project)
+
+	err := withRetry(ctx, func() error {
+		return d.pubsub.Projects.Topics.List(parent).Pages(ctx, func(resp *pubsub.ListTopicsResponse) error {
+			for _, topic := range resp.Topics {
+				list.Append(schema.Resource{
+					Provider:     providerName,
+					ID:           d.id,
+					DNSName:      topic.Name,
+					SelfLink:     topic.Name,
+					Project:      project,
+					ResourceType: pubsubTopicResourceType,
+					Labels:       topic.Labels,
+				})
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = withRetry(ctx, func() error {
+		return d.pubsub.Projects.Subscriptions.List(parent).Pages(ctx, func(resp *pubsub.ListSubscriptionsResponse) error {
+			for _, subscription := range resp.Subscriptions {
+				list.Append(schema.Resource{
+					Provider:     providerName,
+					ID:           d.id,
+					DNSName:      subscription.Name,
+					SelfLink:     subscription.Name,
+					Project:      project,
+					ResourceType: pubsubSubscriptionResourceType,
+					Labels:       subscription.Labels,
+				})
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,63 @@
+package gcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/api/option"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+func newTestPubSubService(t *testing.T, handler http.HandlerFunc) *pubsub.Service {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	svc, err := pubsub.NewService(context.Background(),
+		option.WithHTTPClient(server.Client()),
+		option.WithEndpoint(server.URL),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("could not create test pubsub service: %s", err)
+	}
+	return svc
+}
+
+// TestCloudPubSubProviderFollowsTopicPagination asserts GetResourceForProject
+// drains every page of topics rather than stopping after the first, since a
+// project can have more topics than fit in a single List response.
+func TestCloudPubSubProviderFollowsTopicPagination(t *testing.T) {
+	svc := newTestPubSubService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("pageToken") == "" {
+			_, _ = w.Write([]byte(`{
+				"topics": [{"name": "projects/p/topics/one", "labels": {"env": "prod"}}],
+				"nextPageToken": "page-2"
+			}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"topics": [{"name": "projects/p/topics/two"}]
+		}`))
+	})
+	provider := &cloudPubSubProvider{id: "test", pubsub: svc}
+
+	resources, err := provider.GetResourceForProject(context.Background(), "p")
+	if err != nil {
+		t.Fatalf("GetResourceForProject returned error: %s", err)
+	}
+
+	var names []string
+	for _, resource := range resources.Items {
+		if resource.ResourceType != pubsubTopicResourceType {
+			continue
+		}
+		names = append(names, resource.DNSName)
+	}
+	if len(names) != 2 {
+		t.Fatalf("expected both pages of topics to be collected, got %v", names)
+	}
+}
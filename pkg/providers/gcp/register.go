@@ -0,0 +1,69 @@
+package gcp
+
+import (
+	"context"
+
+	"cloud.google.com/go/compute/metadata"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	errorutil "github.com/projectdiscovery/utils/errors"
+)
+
+// scopes are the OAuth2 scopes requested for every credential source below.
+var scopes = []string{"https://www.googleapis.com/auth/cloud-platform"}
+
+const impersonateServiceAccount = "gcp_impersonate_service_account"
+
+// register resolves GCP credentials from the provided option block, in order
+// of preference:
+//
+//  1. an explicit gcp_service_account_key JSON blob
+//  2. Application Default Credentials (honors GOOGLE_APPLICATION_CREDENTIALS)
+//  3. the GCE/GKE/Cloud Run metadata server token source
+//
+// If gcp_impersonate_service_account is set, the resolved credentials are
+// wrapped so all API calls are made as the target service account, allowing
+// org-wide inventory from a low-privilege bootstrap identity.
+func register(ctx context.Context, options schema.OptionBlock) (option.ClientOption, error) {
+	tokenSource, err := baseTokenSource(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if target, ok := options.GetMetadata(impersonateServiceAccount); ok && target != "" {
+		impersonated, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+			TargetPrincipal: target,
+			Scopes:          scopes,
+		}, option.WithTokenSource(tokenSource))
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not impersonate service account %s", target)
+		}
+		tokenSource = impersonated
+	}
+	return option.WithTokenSource(tokenSource), nil
+}
+
+// baseTokenSource resolves the non-impersonated credentials to use.
+func baseTokenSource(ctx context.Context, options schema.OptionBlock) (oauth2.TokenSource, error) {
+	if jsonData, ok := options.GetMetadata(serviceAccountJSON); ok && jsonData != "" {
+		creds, err := google.CredentialsFromJSON(ctx, []byte(jsonData), scopes...)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not parse gcp service account key")
+		}
+		return creds.TokenSource, nil
+	}
+
+	if creds, err := google.FindDefaultCredentials(ctx, scopes...); err == nil {
+		return creds.TokenSource, nil
+	}
+
+	if metadata.OnGCE() {
+		return google.ComputeTokenSource(""), nil
+	}
+
+	return nil, errorutil.New("could not find gcp credentials: set gcp_service_account_key, GOOGLE_APPLICATION_CREDENTIALS, or run on GCE")
+}
@@ -0,0 +1,63 @@
+package gcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// authorizedUserCredentialsJSON is a syntactically valid "authorized_user"
+// credentials blob. It parses successfully without any network access, which
+// is all baseTokenSource needs to pick a credential source - it never
+// exchanges the refresh token for an access token in these tests.
+func authorizedUserCredentialsJSON(refreshToken string) string {
+	return `{
+		"type": "authorized_user",
+		"client_id": "test-client-id.apps.googleusercontent.com",
+		"client_secret": "test-client-secret",
+		"refresh_token": "` + refreshToken + `"
+	}`
+}
+
+func TestBaseTokenSourceExplicitKeyTakesPriorityOverADC(t *testing.T) {
+	// Point GOOGLE_APPLICATION_CREDENTIALS at a file that can't be parsed as
+	// credentials, so the test fails loudly if the explicit key branch isn't
+	// actually taken first.
+	adcPath := filepath.Join(t.TempDir(), "adc.json")
+	if err := os.WriteFile(adcPath, []byte("not valid credentials json"), 0o600); err != nil {
+		t.Fatalf("could not write fake ADC file: %s", err)
+	}
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", adcPath)
+
+	options := schema.OptionBlock{
+		serviceAccountJSON: authorizedUserCredentialsJSON("explicit-key-token"),
+	}
+
+	if _, err := baseTokenSource(context.Background(), options); err != nil {
+		t.Fatalf("expected explicit gcp_service_account_key to be used ahead of a broken ADC file, got error: %s", err)
+	}
+}
+
+func TestBaseTokenSourceFallsBackToADCWhenNoExplicitKey(t *testing.T) {
+	adcPath := filepath.Join(t.TempDir(), "adc.json")
+	if err := os.WriteFile(adcPath, []byte(authorizedUserCredentialsJSON("adc-token")), 0o600); err != nil {
+		t.Fatalf("could not write ADC file: %s", err)
+	}
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", adcPath)
+
+	if _, err := baseTokenSource(context.Background(), schema.OptionBlock{}); err != nil {
+		t.Fatalf("expected Application Default Credentials to be picked up, got error: %s", err)
+	}
+}
+
+func TestBaseTokenSourceErrorsWhenNoCredentialsAvailable(t *testing.T) {
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", filepath.Join(t.TempDir(), "does-not-exist.json"))
+
+	_, err := baseTokenSource(context.Background(), schema.OptionBlock{})
+	if err == nil {
+		t.Fatal("expected an error when no explicit key, ADC file, or GCE metadata server is available")
+	}
+}
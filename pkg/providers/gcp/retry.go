@@ -0,0 +1,63 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+const maxRetries = 5
+
+// withRetry retries fn with exponential backoff on transient googleapi
+// errors (429, 500, 503), honoring the Retry-After header when the API
+// provides one. fn is called at most maxRetries times in total, and the last
+// error is returned once those calls are used up. If ctx is done while
+// waiting after a failed call, ctx.Err() is returned instead.
+func withRetry(ctx context.Context, fn func() error) error {
+	backoff := time.Second
+
+	var err error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		wait, retryable := retryAfter(err, backoff)
+		if !retryable {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+	return err
+}
+
+// retryAfter reports whether err is a transient googleapi error and, if so,
+// how long to wait before retrying.
+func retryAfter(err error, backoff time.Duration) (time.Duration, bool) {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return 0, false
+	}
+	switch apiErr.Code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable:
+	default:
+		return 0, false
+	}
+
+	for _, header := range apiErr.Header["Retry-After"] {
+		if seconds, err := strconv.Atoi(header); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+	return backoff, true
+}
@@ -0,0 +1,74 @@
+package gcp
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		backoff   time.Duration
+		wantWait  time.Duration
+		wantRetry bool
+	}{
+		{
+			name:      "non-googleapi error is not retryable",
+			err:       errors.New("boom"),
+			backoff:   time.Second,
+			wantWait:  0,
+			wantRetry: false,
+		},
+		{
+			name:      "permanent googleapi error is not retryable",
+			err:       &googleapi.Error{Code: http.StatusNotFound},
+			backoff:   time.Second,
+			wantWait:  0,
+			wantRetry: false,
+		},
+		{
+			name:      "429 without Retry-After falls back to backoff",
+			err:       &googleapi.Error{Code: http.StatusTooManyRequests},
+			backoff:   2 * time.Second,
+			wantWait:  2 * time.Second,
+			wantRetry: true,
+		},
+		{
+			name: "503 honors Retry-After header in seconds",
+			err: &googleapi.Error{
+				Code:   http.StatusServiceUnavailable,
+				Header: http.Header{"Retry-After": []string{"5"}},
+			},
+			backoff:   time.Second,
+			wantWait:  5 * time.Second,
+			wantRetry: true,
+		},
+		{
+			name: "500 with malformed Retry-After falls back to backoff",
+			err: &googleapi.Error{
+				Code:   http.StatusInternalServerError,
+				Header: http.Header{"Retry-After": []string{"not-a-number"}},
+			},
+			backoff:   3 * time.Second,
+			wantWait:  3 * time.Second,
+			wantRetry: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, retryable := retryAfter(tt.err, tt.backoff)
+			if retryable != tt.wantRetry {
+				t.Fatalf("retryable = %v, want %v", retryable, tt.wantRetry)
+			}
+			if wait != tt.wantWait {
+				t.Fatalf("wait = %v, want %v", wait, tt.wantWait)
+			}
+		})
+	}
+}
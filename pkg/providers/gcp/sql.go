@@ -0,0 +1,58 @@
+package gcp
+
+import (
+	"context"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	sqladmin "google.golang.org/api/sqladmin/v1beta4"
+)
+
+// cloudSQLProvider is an instance of Cloud SQL provider for GCP
+type cloudSQLProvider struct {
+	id  string
+	sql *sqladmin.Service
+}
+
+// sqlInstanceResourceType is the Cloud Asset Inventory resource type for a Cloud SQL instance.
+const sqlInstanceResourceType = "sqladmin.googleapis.com/Instance"
+
+// GetResourceForProject returns Cloud SQL instance resources for a single project.
+func (d *cloudSQLProvider) GetResourceForProject(ctx context.Context, project string) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	err := withRetry(ctx, func() error {
+		return d.sql.Instances.List(project).Pages(ctx, func(resp *sqladmin.InstancesListResponse) error {
+			for _, instance := range resp.Items {
+				var labels map[string]string
+				if instance.Settings != nil {
+					labels = instance.Settings.UserLabels
+				}
+				for _, ip := range instance.IpAddresses {
+					resource := schema.Resource{
+						Provider:     providerName,
+						ID:           d.id,
+						DNSName:      instance.Name,
+						SelfLink:     instance.SelfLink,
+						Project:      project,
+						Location:     instance.Region,
+						ResourceType: sqlInstanceResourceType,
+						Labels:       labels,
+					}
+					if ip.Type == "PRIVATE" {
+						resource.Private = true
+						resource.PrivateIpv4 = ip.IpAddress
+					} else {
+						resource.Public = true
+						resource.PublicIPv4 = ip.IpAddress
+					}
+					list.Append(resource)
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
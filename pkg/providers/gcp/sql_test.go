@@ -0,0 +1,79 @@
+package gcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/api/option"
+	sqladmin "google.golang.org/api/sqladmin/v1beta4"
+)
+
+func newTestSQLService(t *testing.T, handler http.HandlerFunc) *sqladmin.Service {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	svc, err := sqladmin.NewService(context.Background(),
+		option.WithHTTPClient(server.Client()),
+		option.WithEndpoint(server.URL),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("could not create test sqladmin service: %s", err)
+	}
+	return svc
+}
+
+func TestCloudSQLProviderClassifiesPublicAndPrivateIPs(t *testing.T) {
+	svc := newTestSQLService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"items": [{
+				"name": "db",
+				"selfLink": "https://sqladmin.googleapis.com/v1/projects/p/instances/db",
+				"region": "us-central1",
+				"ipAddresses": [
+					{"type": "PRIMARY", "ipAddress": "1.2.3.4"},
+					{"type": "PRIVATE", "ipAddress": "10.0.0.5"}
+				],
+				"settings": {"userLabels": {"env": "prod"}}
+			}]
+		}`))
+	})
+	provider := &cloudSQLProvider{id: "test", sql: svc}
+
+	resources, err := provider.GetResourceForProject(context.Background(), "p")
+	if err != nil {
+		t.Fatalf("GetResourceForProject returned error: %s", err)
+	}
+	if len(resources.Items) != 2 {
+		t.Fatalf("expected one resource per IP address, got %d", len(resources.Items))
+	}
+
+	var sawPublic, sawPrivate bool
+	for _, resource := range resources.Items {
+		if resource.ResourceType != sqlInstanceResourceType {
+			t.Fatalf("unexpected resource type %q", resource.ResourceType)
+		}
+		if resource.Labels["env"] != "prod" {
+			t.Fatalf("expected userLabels to be propagated, got %+v", resource.Labels)
+		}
+		switch {
+		case resource.PublicIPv4 == "1.2.3.4":
+			sawPublic = true
+			if !resource.Public || resource.Private {
+				t.Fatalf("PRIMARY address should be classified public, got %+v", resource)
+			}
+		case resource.PrivateIpv4 == "10.0.0.5":
+			sawPrivate = true
+			if !resource.Private || resource.Public {
+				t.Fatalf("PRIVATE address should be classified private, got %+v", resource)
+			}
+		}
+	}
+	if !sawPublic || !sawPrivate {
+		t.Fatalf("expected both a public and a private resource, got %+v", resources.Items)
+	}
+}
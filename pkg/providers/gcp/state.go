@@ -0,0 +1,120 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/storage/v1"
+)
+
+const stateBucketOption = "gcp_state_bucket"
+
+// watchStateStore persists the last Watch snapshot so restarts don't produce
+// a flood of "added" events for resources that were already known about.
+type watchStateStore interface {
+	Load(ctx context.Context) (map[string]schema.Resource, error)
+	Save(ctx context.Context, snapshot map[string]schema.Resource) error
+}
+
+// stateStore returns the configured watchStateStore for this provider: a GCS
+// object when gcp_state_bucket is set, otherwise a local JSON file.
+func (p *Provider) stateStore() watchStateStore {
+	if p.stateBucket != "" && p.storage != nil {
+		return &gcsStateStore{storage: p.storage, bucket: p.stateBucket, object: fmt.Sprintf("cloudlist/gcp-%s.json", p.id)}
+	}
+	return &fileStateStore{path: localStatePath(p.id)}
+}
+
+// localStatePath returns the default on-disk location for a provider's watch
+// state, namespaced by provider id so multiple gcp configs don't collide.
+func localStatePath(id string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "cloudlist", fmt.Sprintf("gcp-%s.json", id))
+}
+
+// fileStateStore persists watch state to a local JSON file.
+type fileStateStore struct {
+	path string
+}
+
+func (f *fileStateStore) Load(_ context.Context) (map[string]schema.Resource, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]schema.Resource{}, nil
+		}
+		return nil, errorutil.NewWithErr(err).Msgf("could not read gcp watch state file")
+	}
+	var snapshot map[string]schema.Resource
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not parse gcp watch state file")
+	}
+	return snapshot, nil
+}
+
+func (f *fileStateStore) Save(_ context.Context, snapshot map[string]schema.Resource) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not marshal gcp watch state")
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not create gcp watch state directory")
+	}
+	return os.WriteFile(f.path, data, 0o644)
+}
+
+// gcsStateStore persists watch state to a GCS object, for restarts that
+// shouldn't rely on local disk (e.g. running cloudlist as a Cloud Run job).
+type gcsStateStore struct {
+	storage *storage.Service
+	bucket  string
+	object  string
+}
+
+func (g *gcsStateStore) Load(ctx context.Context) (map[string]schema.Resource, error) {
+	resp, err := g.storage.Objects.Get(g.bucket, g.object).Context(ctx).Download()
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+			return map[string]schema.Resource{}, nil
+		}
+		return nil, errorutil.NewWithErr(err).Msgf("could not download gcp watch state object")
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not read gcp watch state object")
+	}
+	var snapshot map[string]schema.Resource
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not parse gcp watch state object")
+	}
+	return snapshot, nil
+}
+
+func (g *gcsStateStore) Save(ctx context.Context, snapshot map[string]schema.Resource) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not marshal gcp watch state")
+	}
+	object := &storage.Object{Name: g.object}
+	_, err = g.storage.Objects.Insert(g.bucket, object).Media(bytes.NewReader(data)).Context(ctx).Do()
+	if err != nil {
+		return errorutil.NewWithErr(err).Msgf("could not persist gcp watch state object")
+	}
+	return nil
+}
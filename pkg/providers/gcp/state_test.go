@@ -0,0 +1,53 @@
+package gcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/api/option"
+	storage "google.golang.org/api/storage/v1"
+)
+
+func newTestStorageService(t *testing.T, handler http.HandlerFunc) *storage.Service {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	svc, err := storage.NewService(context.Background(),
+		option.WithHTTPClient(server.Client()),
+		option.WithEndpoint(server.URL),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("could not create test storage service: %s", err)
+	}
+	return svc
+}
+
+func TestGCSStateStoreLoadMissingObjectIsNotAnError(t *testing.T) {
+	svc := newTestStorageService(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"code":404,"message":"not found"}}`, http.StatusNotFound)
+	})
+	store := &gcsStateStore{storage: svc, bucket: "bucket", object: "cloudlist/gcp-test.json"}
+
+	snapshot, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("expected a missing object to be treated as a cold start, got error: %s", err)
+	}
+	if len(snapshot) != 0 {
+		t.Fatalf("expected an empty snapshot, got %d entries", len(snapshot))
+	}
+}
+
+func TestGCSStateStoreLoadPropagatesRealErrors(t *testing.T) {
+	svc := newTestStorageService(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"code":403,"message":"permission denied"}}`, http.StatusForbidden)
+	})
+	store := &gcsStateStore{storage: svc, bucket: "bucket", object: "cloudlist/gcp-test.json"}
+
+	if _, err := store.Load(context.Background()); err == nil {
+		t.Fatal("expected a permissions error to be propagated instead of treated as a cold start")
+	}
+}
@@ -0,0 +1,119 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/projectdiscovery/gologger"
+)
+
+// Watch periodically re-runs Resources and streams a schema.ResourceEvent
+// for every resource added, removed, or changed since the previous snapshot.
+// The previous snapshot is loaded from the configured state store on
+// startup, so a restart doesn't produce a flood of "added" events.
+func (p *Provider) Watch(ctx context.Context, interval time.Duration) (<-chan schema.ResourceEvent, error) {
+	store := p.stateStore()
+
+	previous, err := store.Load(ctx)
+	if err != nil {
+		gologger.Warning().Msgf("Could not load gcp watch state, starting fresh: %s\n", err)
+		previous = make(map[string]schema.Resource)
+	}
+
+	events := make(chan schema.ResourceEvent)
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			current, err := p.snapshot(ctx)
+			if err != nil {
+				gologger.Warning().Msgf("Could not refresh gcp resources: %s\n", err)
+			} else {
+				for _, event := range diffSnapshots(previous, current) {
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+				if err := store.Save(ctx, current); err != nil {
+					gologger.Warning().Msgf("Could not persist gcp watch state: %s\n", err)
+				}
+				previous = current
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return events, nil
+}
+
+// snapshot runs a single Resources call and keys every resource by its
+// identifying fields, for diffing against the previous Watch iteration.
+func (p *Provider) snapshot(ctx context.Context) (map[string]schema.Resource, error) {
+	resources, err := p.Resources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := make(map[string]schema.Resource, len(resources.Items))
+	for _, resource := range resources.Items {
+		snapshot[resourceKey(resource)] = resource
+	}
+	return snapshot, nil
+}
+
+// resourceKey returns the key used to identify a resource across snapshots,
+// preferring its GCP-native SelfLink and falling back to a composite of its
+// flat fields (including Project and ResourceType) for collectors that don't
+// populate one, so e.g. two identically-named resources in different
+// projects don't collide on the same key.
+func resourceKey(r schema.Resource) string {
+	if r.SelfLink != "" {
+		return r.SelfLink
+	}
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s", r.Provider, r.Project, r.ResourceType, r.DNSName, r.PublicIPv4, r.PrivateIpv4)
+}
+
+// diffSnapshots returns Added/Changed events for everything in current not
+// identical to previous, and Removed events for anything that dropped out.
+// It's a pure function over two snapshots so it can run without a channel or
+// context; Watch is responsible for delivering the returned events.
+func diffSnapshots(previous, current map[string]schema.Resource) []schema.ResourceEvent {
+	var events []schema.ResourceEvent
+	for key, resource := range current {
+		old, existed := previous[key]
+		switch {
+		case !existed:
+			events = append(events, schema.ResourceEvent{Type: schema.ResourceAdded, Resource: resource})
+		case !resourcesEqual(old, resource):
+			events = append(events, schema.ResourceEvent{Type: schema.ResourceChanged, Resource: resource})
+		}
+	}
+	for key, resource := range previous {
+		if _, stillPresent := current[key]; !stillPresent {
+			events = append(events, schema.ResourceEvent{Type: schema.ResourceRemoved, Resource: resource})
+		}
+	}
+	return events
+}
+
+// resourcesEqual compares two resources field by field. schema.Resource
+// embeds a map, so it isn't comparable with ==.
+func resourcesEqual(a, b schema.Resource) bool {
+	if a.Provider != b.Provider || a.ID != b.ID || a.Public != b.Public || a.PublicIPv4 != b.PublicIPv4 ||
+		a.Private != b.Private || a.PrivateIpv4 != b.PrivateIpv4 || a.DNSName != b.DNSName ||
+		a.SelfLink != b.SelfLink || a.Project != b.Project || a.Location != b.Location || a.ResourceType != b.ResourceType {
+		return false
+	}
+	return reflect.DeepEqual(a.Labels, b.Labels)
+}
@@ -0,0 +1,88 @@
+package gcp
+
+import (
+	"testing"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+func eventKeys(t *testing.T, events []schema.ResourceEvent) map[string]schema.EventType {
+	t.Helper()
+	out := make(map[string]schema.EventType, len(events))
+	for _, event := range events {
+		out[resourceKey(event.Resource)] = event.Type
+	}
+	return out
+}
+
+func TestDiffSnapshotsAddedRemovedChanged(t *testing.T) {
+	stable := schema.Resource{Provider: providerName, SelfLink: "stable", DNSName: "stable.example.com"}
+	removed := schema.Resource{Provider: providerName, SelfLink: "removed", DNSName: "removed.example.com"}
+	changedOld := schema.Resource{Provider: providerName, SelfLink: "changed", PublicIPv4: "1.1.1.1"}
+	changedNew := schema.Resource{Provider: providerName, SelfLink: "changed", PublicIPv4: "2.2.2.2"}
+	added := schema.Resource{Provider: providerName, SelfLink: "added", DNSName: "added.example.com"}
+
+	previous := map[string]schema.Resource{
+		resourceKey(stable):     stable,
+		resourceKey(removed):    removed,
+		resourceKey(changedOld): changedOld,
+	}
+	current := map[string]schema.Resource{
+		resourceKey(stable):     stable,
+		resourceKey(changedNew): changedNew,
+		resourceKey(added):      added,
+	}
+
+	events := diffSnapshots(previous, current)
+	got := eventKeys(t, events)
+
+	want := map[string]schema.EventType{
+		resourceKey(added):      schema.ResourceAdded,
+		resourceKey(changedNew): schema.ResourceChanged,
+		resourceKey(removed):    schema.ResourceRemoved,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(got), len(want), events)
+	}
+	for key, wantType := range want {
+		gotType, ok := got[key]
+		if !ok {
+			t.Fatalf("missing event for key %q", key)
+		}
+		if gotType != wantType {
+			t.Fatalf("event for key %q = %s, want %s", key, gotType, wantType)
+		}
+	}
+}
+
+func TestDiffSnapshotsNoChanges(t *testing.T) {
+	resource := schema.Resource{Provider: providerName, SelfLink: "stable"}
+	snapshot := map[string]schema.Resource{resourceKey(resource): resource}
+
+	events := diffSnapshots(snapshot, snapshot)
+	if len(events) != 0 {
+		t.Fatalf("expected no events for an unchanged snapshot, got %+v", events)
+	}
+}
+
+func TestResourceKeyFallbackIncludesProject(t *testing.T) {
+	a := schema.Resource{Provider: providerName, Project: "project-a", ResourceType: "container.googleapis.com/Cluster", DNSName: "prod"}
+	b := schema.Resource{Provider: providerName, Project: "project-b", ResourceType: "container.googleapis.com/Cluster", DNSName: "prod"}
+
+	if resourceKey(a) == resourceKey(b) {
+		t.Fatalf("expected resources in different projects to have distinct keys, both got %q", resourceKey(a))
+	}
+}
+
+func TestResourcesEqual(t *testing.T) {
+	a := schema.Resource{Provider: providerName, DNSName: "x", Labels: map[string]string{"env": "prod"}}
+	b := schema.Resource{Provider: providerName, DNSName: "x", Labels: map[string]string{"env": "prod"}}
+	c := schema.Resource{Provider: providerName, DNSName: "x", Labels: map[string]string{"env": "dev"}}
+
+	if !resourcesEqual(a, b) {
+		t.Fatal("expected identical resources to be equal")
+	}
+	if resourcesEqual(a, c) {
+		t.Fatal("expected resources with different labels to be unequal")
+	}
+}
@@ -0,0 +1,119 @@
+package schema
+
+import (
+	"context"
+	"time"
+)
+
+// OptionBlock is a block of options for a provider as parsed from the
+// cloudlist config file.
+type OptionBlock map[string]interface{}
+
+// GetMetadata returns a string metadata value from the option block.
+func (o OptionBlock) GetMetadata(key string) (string, bool) {
+	value, ok := o[key]
+	if !ok {
+		return "", false
+	}
+	str, ok := value.(string)
+	return str, ok
+}
+
+// ServiceMap is a set of services requested by the user for a provider.
+type ServiceMap map[string]struct{}
+
+// Has returns true if the service is present in the map.
+func (s ServiceMap) Has(name string) bool {
+	_, ok := s[name]
+	return ok
+}
+
+// Keys returns the list of services present in the map.
+func (s ServiceMap) Keys() []string {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Provider is implemented by every cloud provider cloudlist supports.
+type Provider interface {
+	// Name returns the name of the provider
+	Name() string
+	// ID returns the id of the provider
+	ID() string
+	// Resources returns the resources for the provider
+	Resources(ctx context.Context) (*Resources, error)
+}
+
+// Resource is a single cloud asset discovered by a provider.
+type Resource struct {
+	Provider    string
+	ID          string
+	Public      bool
+	PublicIPv4  string
+	Private     bool
+	PrivateIpv4 string
+	DNSName     string
+
+	// SelfLink, Project, Location, ResourceType, and Labels carry the
+	// provider-native identity of the resource. They are populated on a
+	// best-effort basis by providers that have this metadata available, and
+	// are empty for providers/collectors that only expose flat host/IP data.
+	SelfLink     string
+	Project      string
+	Location     string
+	ResourceType string
+	Labels       map[string]string
+}
+
+// Resources is a collection of discovered resources.
+type Resources struct {
+	Items []Resource
+}
+
+// NewResources returns a new, empty Resources collection.
+func NewResources() *Resources {
+	return &Resources{}
+}
+
+// Append adds a single resource to the collection.
+func (r *Resources) Append(resource Resource) {
+	r.Items = append(r.Items, resource)
+}
+
+// Merge merges another Resources collection into this one.
+func (r *Resources) Merge(other *Resources) {
+	if other == nil {
+		return
+	}
+	r.Items = append(r.Items, other.Items...)
+}
+
+// EventType describes what happened to a resource between two Watch iterations.
+type EventType string
+
+const (
+	// ResourceAdded is emitted the first time a resource is seen.
+	ResourceAdded EventType = "added"
+	// ResourceRemoved is emitted when a previously seen resource disappears.
+	ResourceRemoved EventType = "removed"
+	// ResourceChanged is emitted when a previously seen resource's fields differ.
+	ResourceChanged EventType = "changed"
+)
+
+// ResourceEvent is a single drift event emitted by a Watcher.
+type ResourceEvent struct {
+	Type     EventType
+	Resource Resource
+}
+
+// Watcher is implemented by providers that support polling for resource
+// drift over time instead of a single one-shot Resources call.
+type Watcher interface {
+	// Watch periodically re-runs resource discovery and streams a
+	// ResourceEvent for every resource added, removed, or changed since the
+	// previous iteration. The returned channel is closed when ctx is done.
+	Watch(ctx context.Context, interval time.Duration) (<-chan ResourceEvent, error)
+}